@@ -0,0 +1,63 @@
+// Copyright 2021 Matrix Origin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+// RuntimeFilter_Type is the kind of pruning a build side hands a probe side
+// through a RuntimeFilterSender: an exact IN-list, a probabilistic bloom
+// filter for builds too large for an IN-list but still worth pruning, a
+// no-op PASS, or a DROP telling the probe side its input is empty.
+type RuntimeFilter_Type int32
+
+const (
+	RuntimeFilter_IN RuntimeFilter_Type = iota
+	RuntimeFilter_BLOOM
+	RuntimeFilter_PASS
+	RuntimeFilter_DROP
+)
+
+// RuntimeFilter is the payload a build-side operator (e.g. indexbuild) sends
+// a probe-side operator once its build set is known. Data's shape depends on
+// Typ: an IN-list carries a vector.Vector.MarshalBinary encoding, BLOOM
+// carries the runtimefilter.DecodeBloomFilter-compatible encoding built by
+// runtimefilter.BuildBloomFilter; PASS and DROP carry no Data.
+type RuntimeFilter struct {
+	Typ  RuntimeFilter_Type
+	Card int32
+	Data []byte
+}
+
+// RuntimeFilterSpec is the probe side's half of a RuntimeFilterSender: the
+// expression being filtered on, and the cardinality limits that decide which
+// RuntimeFilter_Type the build side degrades to as its build set grows.
+type RuntimeFilterSpec struct {
+	// Expr is the probe-side expression the runtime filter prunes against.
+	// It is nil when the probe side has nothing to filter on, in which case
+	// the build side always responds with RuntimeFilter_PASS.
+	Expr *Expr
+
+	// UpperLimit is the build-side row count at or under which an IN-list
+	// (RuntimeFilter_IN) is built.
+	UpperLimit int32
+
+	// BloomLimit is the build-side row count at or under which a bloom
+	// filter (RuntimeFilter_BLOOM) is built once UpperLimit is exceeded.
+	// Zero or negative disables the bloom path: builds over UpperLimit
+	// degrade straight to RuntimeFilter_PASS.
+	BloomLimit int32
+}
+
+// Expr is a placeholder for the probe-side filter expression type (normally
+// a *plan.Expr); only nil-ness is significant to this package's callers.
+type Expr struct{}
@@ -0,0 +1,73 @@
+// Copyright 2021 Matrix Origin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perfcounter
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Counter is a simple atomic accumulator. Callers only ever Add to or Load
+// it; there's no reset, since a CounterSet's lifetime is the ctx it's
+// attached to.
+type Counter struct {
+	n int64
+}
+
+func (c *Counter) Add(delta int64) {
+	atomic.AddInt64(&c.n, delta)
+}
+
+func (c *Counter) Load() int64 {
+	return atomic.LoadInt64(&c.n)
+}
+
+// CounterSet is the perf-counter tree threaded through ctx by Update. It
+// only declares the fields this repo's callers touch.
+type CounterSet struct {
+	TAE struct {
+		Object struct {
+			// MergeBlocks counts completed mergeObjectsTask runs.
+			MergeBlocks Counter
+
+			// MergeSpillRounds and MergeSpillBytes track how often and how
+			// much a merge had to spill intermediate rounds to rt.Fs instead
+			// of merging its input in one in-memory pass (see
+			// jobs.mergeObjectsTask.runBoundedRounds).
+			MergeSpillRounds Counter
+			MergeSpillBytes  Counter
+
+			// MergePeakBytes is the largest resident size any single round
+			// of a merge held at once.
+			MergePeakBytes Counter
+		}
+	}
+}
+
+type counterSetKey struct{}
+
+// Update applies fn to the CounterSet stashed in ctx, if any, so callers
+// that don't care about collecting stats for a given ctx can call this
+// unconditionally.
+func Update(ctx context.Context, fn func(counter *CounterSet)) {
+	if cs, ok := ctx.Value(counterSetKey{}).(*CounterSet); ok {
+		fn(cs)
+	}
+}
+
+// WithCounterSet returns a ctx that Update will report counters into.
+func WithCounterSet(ctx context.Context, cs *CounterSet) context.Context {
+	return context.WithValue(ctx, counterSetKey{}, cs)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/matrixorigin/matrixone/pkg/container/batch"
 	"github.com/matrixorigin/matrixone/pkg/pb/pipeline"
+	"github.com/matrixorigin/matrixone/pkg/sql/colexec/runtimefilter"
 	"github.com/matrixorigin/matrixone/pkg/vm"
 	"github.com/matrixorigin/matrixone/pkg/vm/process"
 )
@@ -113,8 +114,16 @@ func (ctr *container) collectBuildBatches(ap *Argument, proc *process.Process, a
 			return err
 		}
 		proc.PutBatch(currentBatch)
-		if ctr.batch.RowCount() > int(ap.RuntimeFilterSenders[0].Spec.UpperLimit) {
-			// for index build, can exit early
+		// Past the IN-list limit alone we can't exit early: we still need
+		// every row up to BloomLimit so the bloom-filter path (see
+		// handleRuntimeFilter) isn't built off a truncated sample. Only once
+		// we're past both limits is further accumulation pointless: the
+		// filter degrades to PASS regardless of how many more rows we see.
+		exitLimit := ap.RuntimeFilterSenders[0].Spec.UpperLimit
+		if bloomLimit := ap.RuntimeFilterSenders[0].Spec.BloomLimit; bloomLimit > exitLimit {
+			exitLimit = bloomLimit
+		}
+		if ctr.batch.RowCount() > int(exitLimit) {
 			return nil
 		}
 	}
@@ -148,15 +157,14 @@ func (ctr *container) handleRuntimeFilter(ap *Argument, proc *process.Process) e
 	}
 
 	inFilterCardLimit := ap.RuntimeFilterSenders[0].Spec.UpperLimit
+	bloomLimit := ap.RuntimeFilterSenders[0].Spec.BloomLimit
 
-	if ctr.batch.RowCount() > int(inFilterCardLimit) {
-		runtimeFilter = &pipeline.RuntimeFilter{
-			Typ: pipeline.RuntimeFilter_PASS,
-		}
-	} else {
-		if len(ctr.batch.Vecs) != 1 {
-			panic("there must be only 1 vector in index build batch")
-		}
+	if len(ctr.batch.Vecs) != 1 {
+		panic("there must be only 1 vector in index build batch")
+	}
+
+	switch runtimeFilterKind(ctr.batch.RowCount(), int(inFilterCardLimit), int(bloomLimit)) {
+	case pipeline.RuntimeFilter_IN:
 		vec := ctr.batch.Vecs[0]
 		vec.InplaceSort()
 		data, err := vec.MarshalBinary()
@@ -169,11 +177,45 @@ func (ctr *container) handleRuntimeFilter(ap *Argument, proc *process.Process) e
 			Card: int32(vec.Length()),
 			Data: data,
 		}
+	case pipeline.RuntimeFilter_BLOOM:
+		// Too many rows for an IN-list, but still cheap enough to build a
+		// bloom filter: skip the sort entirely and hash the raw vector bytes.
+		data, err := runtimefilter.BuildBloomFilter(ctr.batch.Vecs[0])
+		if err != nil {
+			return err
+		}
+
+		runtimeFilter = &pipeline.RuntimeFilter{
+			Typ:  pipeline.RuntimeFilter_BLOOM,
+			Card: int32(ctr.batch.Vecs[0].Length()),
+			Data: data,
+		}
+	default:
+		runtimeFilter = &pipeline.RuntimeFilter{
+			Typ: pipeline.RuntimeFilter_PASS,
+		}
 	}
 	sendFilter(ap, proc, runtimeFilter)
 	return nil
 }
 
+// runtimeFilterKind picks which kind of runtime filter handleRuntimeFilter
+// builds for a build side of rowCount rows: an IN-list at or under
+// inFilterCardLimit, a bloom filter above that up to bloomLimit (when
+// bloomLimit enables it), or PASS once even a bloom filter isn't worth
+// building. Pulled out of handleRuntimeFilter so the degeneracy thresholds
+// can be tested without a process/container.
+func runtimeFilterKind(rowCount int, inFilterCardLimit, bloomLimit int) pipeline.RuntimeFilter_Type {
+	switch {
+	case rowCount <= inFilterCardLimit:
+		return pipeline.RuntimeFilter_IN
+	case bloomLimit > 0 && rowCount <= bloomLimit:
+		return pipeline.RuntimeFilter_BLOOM
+	default:
+		return pipeline.RuntimeFilter_PASS
+	}
+}
+
 func sendFilter(ap *Argument, proc *process.Process, runtimeFilter *pipeline.RuntimeFilter) {
 	anal := proc.GetAnalyze(ap.GetIdx(), ap.GetParallelIdx(), ap.GetParallelMajor())
 	sendRuntimeFilterStart := time.Now()
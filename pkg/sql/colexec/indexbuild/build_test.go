@@ -0,0 +1,39 @@
+// Copyright 2021 Matrix Origin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexbuild
+
+import (
+	"testing"
+
+	"github.com/matrixorigin/matrixone/pkg/pb/pipeline"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeFilterKindDegeneracy(t *testing.T) {
+	const inLimit, bloomLimit = 100, 10000
+
+	require.Equal(t, pipeline.RuntimeFilter_IN, runtimeFilterKind(inLimit, inLimit, bloomLimit))
+	require.Equal(t, pipeline.RuntimeFilter_BLOOM, runtimeFilterKind(inLimit+1, inLimit, bloomLimit))
+	require.Equal(t, pipeline.RuntimeFilter_BLOOM, runtimeFilterKind(bloomLimit, inLimit, bloomLimit))
+
+	// Above bloomLimit, the filter degrades to PASS regardless of how far
+	// over it is.
+	require.Equal(t, pipeline.RuntimeFilter_PASS, runtimeFilterKind(bloomLimit+1, inLimit, bloomLimit))
+	require.Equal(t, pipeline.RuntimeFilter_PASS, runtimeFilterKind(bloomLimit*100, inLimit, bloomLimit))
+
+	// bloomLimit <= 0 means the bloom path is disabled entirely: anything
+	// above inLimit goes straight to PASS.
+	require.Equal(t, pipeline.RuntimeFilter_PASS, runtimeFilterKind(inLimit+1, inLimit, 0))
+}
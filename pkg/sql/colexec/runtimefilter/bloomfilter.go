@@ -0,0 +1,183 @@
+// Copyright 2021 Matrix Origin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runtimefilter holds the RuntimeFilter_BLOOM encode/decode logic
+// shared by a build-side operator (e.g. indexbuild, which calls
+// BuildBloomFilter) and whatever probe-side operator receives the resulting
+// pipeline.RuntimeFilter off its RuntimeFilterSender.Chan (which calls
+// ProbeBloomRuntimeFilter). Neither side should need to import the other's
+// operator package just to speak the wire format, the same way
+// RuntimeFilter_IN is decoded and probed without depending on the build-side
+// operator that produced it.
+package runtimefilter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/matrixorigin/matrixone/pkg/container/vector"
+	"github.com/matrixorigin/matrixone/pkg/pb/pipeline"
+)
+
+// bloomFilterFPR is the target false-positive rate used to size the bitset
+// for a RuntimeFilter_BLOOM payload (see bloomParams).
+const bloomFilterFPR = 0.01
+
+// bloomHeaderSize is the length, in bytes, of the fixed header written ahead
+// of the bitset in a bloom RuntimeFilter's Data: m, k, seed, n, each uint64.
+const bloomHeaderSize = 8 * 4
+
+// BuildBloomFilter hashes every row of vec directly off its raw bytes (no
+// InplaceSort, unlike the IN-list path) and serializes the resulting bitset
+// as: m (bits) | k (hash count) | seed | n (element count) | bitset.
+func BuildBloomFilter(vec *vector.Vector) ([]byte, error) {
+	n := vec.Length()
+	m, k := bloomParams(n, bloomFilterFPR)
+	seed := uint64(0x9e3779b97f4a7c15)
+
+	bits := make([]byte, (m+7)/8)
+	for row := 0; row < n; row++ {
+		if vec.IsNull(uint64(row)) {
+			continue
+		}
+		h1, h2 := bloomHashPair(vec.GetRawBytesAt(row), seed)
+		setBloomBits(bits, m, k, h1, h2)
+	}
+
+	buf := make([]byte, bloomHeaderSize+len(bits))
+	binary.LittleEndian.PutUint64(buf[0:8], uint64(m))
+	binary.LittleEndian.PutUint64(buf[8:16], uint64(k))
+	binary.LittleEndian.PutUint64(buf[16:24], seed)
+	binary.LittleEndian.PutUint64(buf[24:32], uint64(n))
+	copy(buf[bloomHeaderSize:], bits)
+	return buf, nil
+}
+
+// BloomFilter is the probe-side decoding of a RuntimeFilter_BLOOM payload.
+type BloomFilter struct {
+	m, k, seed, n uint64
+	bits          []byte
+}
+
+// DecodeBloomFilter parses the RuntimeFilter.Data payload BuildBloomFilter
+// produced. Callers receiving a pipeline.RuntimeFilter with Typ ==
+// RuntimeFilter_BLOOM must go through this (or treat the filter as PASS)
+// rather than attempt to read Data directly.
+func DecodeBloomFilter(data []byte) (*BloomFilter, error) {
+	if len(data) < bloomHeaderSize {
+		return nil, fmt.Errorf("bloom filter data too short: %d bytes", len(data))
+	}
+	bf := &BloomFilter{
+		m:    binary.LittleEndian.Uint64(data[0:8]),
+		k:    binary.LittleEndian.Uint64(data[8:16]),
+		seed: binary.LittleEndian.Uint64(data[16:24]),
+		n:    binary.LittleEndian.Uint64(data[24:32]),
+		bits: data[bloomHeaderSize:],
+	}
+	// m==0 or k==0 can never come from BuildBloomFilter (bloomParams floors
+	// both at 1), so either means corrupt or hostile input. Reject them here
+	// rather than let Contains divide by bf.m.
+	if bf.m == 0 || bf.k == 0 {
+		return nil, fmt.Errorf("bloom filter header invalid: m=%d, k=%d", bf.m, bf.k)
+	}
+	if uint64(len(bf.bits)) < (bf.m+7)/8 {
+		return nil, fmt.Errorf("bloom filter bitset truncated: want %d bits, got %d bytes", bf.m, len(bf.bits))
+	}
+	return bf, nil
+}
+
+// Contains reports whether row of vec may be present in the filter. False
+// positives are possible (bounded by bloomFilterFPR at build time); false
+// negatives are not.
+func (bf *BloomFilter) Contains(vec *vector.Vector, row int) bool {
+	if vec.IsNull(uint64(row)) {
+		// BuildBloomFilter never inserts null build-side rows, so a null
+		// probe row can never be a match.
+		return false
+	}
+	h1, h2 := bloomHashPair(vec.GetRawBytesAt(row), bf.seed)
+	for i := uint64(0); i < bf.k; i++ {
+		bit := (h1 + i*h2) % bf.m
+		if bf.bits[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ProbeBloomRuntimeFilter is the probe-side entry point for a
+// pipeline.RuntimeFilter with Typ == RuntimeFilter_BLOOM: it decodes rf.Data
+// and reports, for every row of vec, whether that row may still match. A
+// probe operator receiving rf off its RuntimeFilterSender.Chan must go
+// through this rather than inspect rf.Data itself, since the encoding is a
+// private detail of BuildBloomFilter/DecodeBloomFilter.
+func ProbeBloomRuntimeFilter(rf *pipeline.RuntimeFilter, vec *vector.Vector) ([]bool, error) {
+	if rf.Typ != pipeline.RuntimeFilter_BLOOM {
+		return nil, fmt.Errorf("ProbeBloomRuntimeFilter: runtime filter type is %v, not RuntimeFilter_BLOOM", rf.Typ)
+	}
+	bf, err := DecodeBloomFilter(rf.Data)
+	if err != nil {
+		return nil, err
+	}
+	keep := make([]bool, vec.Length())
+	for row := range keep {
+		keep[row] = bf.Contains(vec, row)
+	}
+	return keep, nil
+}
+
+// bloomParams sizes a bloom filter for n elements at the given target false
+// positive rate, returning (m bits, k hash functions).
+func bloomParams(n int, fpr float64) (m, k uint64) {
+	if n <= 0 {
+		n = 1
+	}
+	mf := -float64(n) * math.Log(fpr) / (math.Ln2 * math.Ln2)
+	m = uint64(math.Ceil(mf))
+	if m == 0 {
+		m = 1
+	}
+	kf := (mf / float64(n)) * math.Ln2
+	k = uint64(math.Round(kf))
+	if k == 0 {
+		k = 1
+	}
+	return m, k
+}
+
+func setBloomBits(bits []byte, m, k, h1, h2 uint64) {
+	for i := uint64(0); i < k; i++ {
+		bit := (h1 + i*h2) % m
+		bits[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// bloomHashPair derives two independent hashes from raw bytes via Kirsch-
+// Mitzenmacher double hashing, avoiding k independent hash functions.
+func bloomHashPair(data []byte, seed uint64) (uint64, uint64) {
+	return fnv64a(data, seed), fnv64a(data, seed^0xff51afd7ed558ccd)
+}
+
+// fnv64a is a seeded FNV-1a variant; it's only used to spread bloom filter
+// bits, not as a general-purpose hash.
+func fnv64a(data []byte, seed uint64) uint64 {
+	const prime64 = 1099511628211
+	h := seed
+	for _, b := range data {
+		h ^= uint64(b)
+		h *= prime64
+	}
+	return h
+}
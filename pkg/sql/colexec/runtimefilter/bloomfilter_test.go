@@ -0,0 +1,131 @@
+// Copyright 2021 Matrix Origin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimefilter
+
+import (
+	"encoding/binary"
+	"strconv"
+	"testing"
+
+	"github.com/matrixorigin/matrixone/pkg/common/mpool"
+	"github.com/matrixorigin/matrixone/pkg/container/types"
+	"github.com/matrixorigin/matrixone/pkg/container/vector"
+	"github.com/matrixorigin/matrixone/pkg/pb/pipeline"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilterRoundTrip(t *testing.T) {
+	mp := mpool.MustNewZero()
+	vec := vector.NewVec(types.T_int64.ToType())
+	defer vec.Free(mp)
+
+	const n = 5000
+	vals := make([]int64, n)
+	for i := range vals {
+		vals[i] = int64(i)
+	}
+	require.NoError(t, vector.AppendFixedList(vec, vals, nil, mp))
+
+	data, err := BuildBloomFilter(vec)
+	require.NoError(t, err)
+
+	bf, err := DecodeBloomFilter(data)
+	require.NoError(t, err)
+
+	probe := vector.NewVec(types.T_int64.ToType())
+	defer probe.Free(mp)
+	require.NoError(t, vector.AppendFixedList(probe, []int64{0, 1, n / 2, n - 1}, nil, mp))
+	for row := 0; row < probe.Length(); row++ {
+		require.True(t, bf.Contains(probe, row), "row %d of the build set must never be a false negative", row)
+	}
+
+	// A value outside the build set can false-positive at roughly
+	// bloomFilterFPR, but the filter is deterministic (fixed seed and
+	// sizing), and -1 does not collide with the 0..n-1 build set.
+	miss := vector.NewVec(types.T_int64.ToType())
+	defer miss.Free(mp)
+	require.NoError(t, vector.AppendFixedList(miss, []int64{-1}, nil, mp))
+	require.False(t, bf.Contains(miss, 0))
+}
+
+func TestDecodeBloomFilterRejectsTruncatedData(t *testing.T) {
+	_, err := DecodeBloomFilter([]byte("too short"))
+	require.Error(t, err)
+
+	mp := mpool.MustNewZero()
+	vec := vector.NewVec(types.T_int64.ToType())
+	defer vec.Free(mp)
+	vals := make([]int64, 1000)
+	for i := range vals {
+		vals[i] = int64(i)
+	}
+	require.NoError(t, vector.AppendFixedList(vec, vals, nil, mp))
+
+	data, err := BuildBloomFilter(vec)
+	require.NoError(t, err)
+
+	_, err = DecodeBloomFilter(data[:len(data)-1])
+	require.Error(t, err)
+}
+
+func TestDecodeBloomFilterRejectsZeroHeaderFields(t *testing.T) {
+	zeroM := make([]byte, bloomHeaderSize+1)
+	binary.LittleEndian.PutUint64(zeroM[8:16], 1) // k=1, m left at 0
+	_, err := DecodeBloomFilter(zeroM)
+	require.Error(t, err, "m=0 must be rejected, not let Contains divide by it")
+
+	zeroK := make([]byte, bloomHeaderSize+1)
+	binary.LittleEndian.PutUint64(zeroK[0:8], 8) // m=8, k left at 0
+	_, err = DecodeBloomFilter(zeroK)
+	require.Error(t, err, "k=0 must be rejected")
+}
+
+func TestProbeBloomRuntimeFilter(t *testing.T) {
+	mp := mpool.MustNewZero()
+	vec := vector.NewVec(types.T_int64.ToType())
+	defer vec.Free(mp)
+
+	const n = 5000
+	vals := make([]int64, n)
+	for i := range vals {
+		vals[i] = int64(i)
+	}
+	require.NoError(t, vector.AppendFixedList(vec, vals, nil, mp))
+
+	data, err := BuildBloomFilter(vec)
+	require.NoError(t, err)
+	rf := &pipeline.RuntimeFilter{Typ: pipeline.RuntimeFilter_BLOOM, Data: data}
+
+	probe := vector.NewVec(types.T_int64.ToType())
+	defer probe.Free(mp)
+	require.NoError(t, vector.AppendFixedList(probe, []int64{0, 1, n / 2, n - 1}, nil, mp))
+
+	keep, err := ProbeBloomRuntimeFilter(rf, probe)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, true, true, true}, keep)
+
+	_, err = ProbeBloomRuntimeFilter(&pipeline.RuntimeFilter{Typ: pipeline.RuntimeFilter_PASS}, probe)
+	require.Error(t, err, "ProbeBloomRuntimeFilter must reject non-BLOOM filters rather than misinterpret their Data")
+}
+
+func TestBloomParamsScaleWithN(t *testing.T) {
+	prevM := uint64(0)
+	for _, n := range []int{1, 100, 10000, 1000000} {
+		m, k := bloomParams(n, bloomFilterFPR)
+		require.Greater(t, m, prevM, "m should grow with n (n=%s)", strconv.Itoa(n))
+		require.Greater(t, k, uint64(0))
+		prevM = m
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 Matrix Origin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbutils
+
+import (
+	"github.com/matrixorigin/matrixone/pkg/fileservice"
+	"github.com/matrixorigin/matrixone/pkg/vm/engine/tae/catalog"
+	"github.com/matrixorigin/matrixone/pkg/vm/engine/tae/containers"
+	"github.com/matrixorigin/matrixone/pkg/vm/engine/tae/mergesort"
+)
+
+// Runtime bundles the shared, long-lived services a TAE transaction or
+// background task needs: storage, vector pooling, and the optional hooks
+// and policy knobs that let other subsystems observe or bound catalog
+// changes.
+type Runtime struct {
+	Fs         *Fs
+	VectorPool *VectorPoolGroup
+
+	// MergeEventSink, when set, is notified once a merge (compaction) entry
+	// has been logged into its transaction. The default is nil (no-op); CDC-style
+	// consumers plug in their own to remap or invalidate rowid->pk caches,
+	// since rowids of non-appendable objects change when they are rewritten.
+	MergeEventSink MergeEventSink
+
+	// MergePolicy bounds how much of a merge's input a task is willing to
+	// hold resident at once before splitting it into budget-sized rounds
+	// (see jobs.mergeObjectsTask.runBoundedRounds). The zero value keeps the
+	// previous, unbounded behavior.
+	MergePolicy MergePolicy
+}
+
+// Fs wraps the file service a Runtime's tasks write object data through.
+type Fs struct {
+	Service fileservice.FileService
+}
+
+// VectorPoolGroup groups the vector pools a Runtime hands out by lifetime;
+// Transient is used for data that doesn't outlive a single task.
+type VectorPoolGroup struct {
+	Transient *containers.VectorPool
+}
+
+// MergeEventSink receives a notification for every merge (compaction) whose
+// entry HandleMergeEntryInTxn has logged into its transaction (txn.LogTxnEntry
+// succeeded). Implementations can use it to remap or
+// invalidate rowid->pk caches, since rowids of non-appendable objects change
+// when they are rewritten by a merge. The default sink used by Runtime is a
+// no-op; CDC-style consumers (see pkg/cdc) plug in their own.
+//
+// This interface lives on dbutils.Runtime rather than in jobs (the package
+// that actually calls OnMerge, from HandleMergeEntryInTxn): jobs already
+// imports dbutils for Runtime itself, so defining the sink here lets jobs
+// read it off rt.MergeEventSink without dbutils ever needing to import back
+// into jobs.
+//
+// OnMerge runs synchronously inside the merge task, after txn.LogTxnEntry
+// and before the transaction commits, so a transaction that aborts after
+// that point has still had its merge reported. Implementations must be fast
+// and non-blocking; slow work (network calls, contended locks) should be
+// handed off to the sink's own goroutine/queue rather than done inline, or
+// it will stall the merge task.
+type MergeEventSink interface {
+	OnMerge(entry *mergesort.MergeCommitEntry, created []*catalog.ObjectEntry)
+}
+
+// MergePolicy configures the memory budget a merge task uses to decide how
+// many blocks it may hold resident before splitting the work into several
+// smaller, spilled rounds.
+//
+// MemBudget only bounds jobs.mergeObjectsTask's intermediate reduction
+// passes (see runBoundedRounds): the task's final mergesort.DoMergeAndWrite
+// call still materializes its whole input at once, so a merge large enough
+// to need bounding at all will still peak at roughly its full input size.
+// MergePeakBytes reports that true peak, bounded passes or not.
+type MergePolicy struct {
+	// MemBudget is the approximate number of bytes a merge round may hold
+	// resident. Zero disables bounding, restoring the single-pass behavior.
+	MemBudget int64
+}
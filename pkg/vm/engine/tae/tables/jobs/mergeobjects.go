@@ -40,89 +40,130 @@ import (
 	"github.com/matrixorigin/matrixone/pkg/vm/engine/tae/tasks"
 )
 
-type mergeObjectsTask struct {
-	*tasks.BaseTask
-	txn               txnif.AsyncTxn
-	rt                *dbutils.Runtime
-	mergedObjs        []*catalog.ObjectEntry
-	mergedObjsHandle  []handle.Object
-	mergedBlkCnt      []int
-	totalMergedBlkCnt int
-	createdBObjs      []*catalog.ObjectEntry
-	commitEntry       *mergesort.MergeCommitEntry
-	rel               handle.Relation
-	did, tid          uint64
+// blockSource is the read surface mergeRound needs from one unit of its
+// input: either a real catalog object (handle.Object already satisfies
+// this) or, for a bounded round's intermediate output, a spillRun read
+// directly off rt.Fs without ever registering it with the catalog.
+type blockSource interface {
+	GetColumnDataByIds(ctx context.Context, blkOffset uint16, colIdxs []int, mp *mpool.MPool) (*containers.BlockView, error)
 }
 
-func NewMergeObjectsTask(
-	ctx *tasks.Context, txn txnif.AsyncTxn,
-	mergedObjs []*catalog.ObjectEntry,
-	rt *dbutils.Runtime,
-) (task *mergeObjectsTask, err error) {
-	if len(mergedObjs) == 0 {
-		panic("empty mergedObjs")
-	}
-	task = &mergeObjectsTask{
-		txn:          txn,
-		rt:           rt,
-		mergedObjs:   mergedObjs,
-		createdBObjs: make([]*catalog.ObjectEntry, 0),
-		mergedBlkCnt: make([]int, len(mergedObjs)),
-	}
-	for i, obj := range mergedObjs {
-		task.mergedBlkCnt[i] = task.totalMergedBlkCnt
-		task.totalMergedBlkCnt += obj.BlockCnt()
-	}
+// spillRun is one bounded round's merged-and-written output. It is a real
+// object on rt.Fs (mergesort.DoMergeAndWrite wrote and sorted it exactly
+// like a committed object would be), but runBoundedRounds deliberately never
+// hands its stats to HandleMergeEntryInTxn: committing it would create a
+// visible, catalog-registered object for data that is really just an
+// internal step of one larger merge, and would fire MergeEventSink once per
+// intermediate round instead of once for the whole task. Reading it back
+// through spillRun instead of rel.GetObject also avoids reading a
+// not-yet-committed catalog object back within the same txn.
+type spillRun struct {
+	fs    *dbutils.Fs
+	stats objectio.ObjectStats
+}
 
-	task.did = mergedObjs[0].GetTable().GetDB().ID
-	database, err := txn.GetDatabaseByID(task.did)
-	if err != nil {
-		return
+func (s *spillRun) GetColumnDataByIds(ctx context.Context, blkOffset uint16, colIdxs []int, mp *mpool.MPool) (*containers.BlockView, error) {
+	return blockio.BlockReadByStats(ctx, s.fs.Service, s.stats, blkOffset, colIdxs, mp)
+}
+
+func (s *spillRun) blockCnt() int {
+	return int(s.stats.BlkCnt())
+}
+
+// path returns the fs path backing this run, so runBoundedRounds can clean
+// it up once a later round has consumed it.
+func (s *spillRun) path() string {
+	return s.stats.ObjectName().String()
+}
+
+// mergeRound holds everything mergesort.DoMergeAndWrite needs to read,
+// merge, and write one batch of objects: the DisposableVecPool methods, the
+// block reader (NextBatch/PrepareData), and the commit entry it fills in as
+// it writes. A plain merge runs a single round over every merged object;
+// mergeObjectsTask.runBoundedRounds chains several rounds, each over a
+// budget-sized subset, when the whole input doesn't fit in memory at once.
+type mergeRound struct {
+	rt       *dbutils.Runtime
+	rel      handle.Relation
+	txn      txnif.AsyncTxn
+	did, tid uint64
+
+	// objs is the set of real, catalog-registered objects this round merges,
+	// used to fill the commit entry's MergedObjs. It's nil for a round whose
+	// input is one or more spillRuns (runBoundedRounds' intermediate
+	// reduction steps), since those were never registered with the catalog
+	// and so have nothing to soft-delete.
+	objs        []*catalog.ObjectEntry
+	sources     []blockSource
+	blkCnt      []int
+	totalBlkCnt int
+
+	// nextBlockCursor tracks progress through NextBatch's streaming read.
+	nextBlockCursor int
+	commitEntry     *mergesort.MergeCommitEntry
+}
+
+func newMergeRound(rt *dbutils.Runtime, rel handle.Relation, txn txnif.AsyncTxn, did, tid uint64, objs []*catalog.ObjectEntry) (*mergeRound, error) {
+	round := &mergeRound{
+		rt:     rt,
+		rel:    rel,
+		txn:    txn,
+		did:    did,
+		tid:    tid,
+		objs:   objs,
+		blkCnt: make([]int, len(objs)),
 	}
-	task.tid = mergedObjs[0].GetTable().ID
-	task.rel, err = database.GetRelationByID(task.tid)
-	if err != nil {
-		return
+	for i, obj := range objs {
+		round.blkCnt[i] = round.totalBlkCnt
+		round.totalBlkCnt += obj.BlockCnt()
 	}
-	for _, meta := range mergedObjs {
-		obj, err := task.rel.GetObject(&meta.ID)
+	for _, meta := range objs {
+		obj, err := rel.GetObject(&meta.ID)
 		if err != nil {
 			return nil, err
 		}
-		task.mergedObjsHandle = append(task.mergedObjsHandle, obj)
+		round.sources = append(round.sources, obj)
 	}
-	task.BaseTask = tasks.NewBaseTask(task, tasks.DataCompactionTask, ctx)
-	return
+	return round, nil
+}
+
+// newSpillMergeRound builds a round over a previous bounded round's
+// intermediate output instead of real catalog objects, so runBoundedRounds
+// can chain spilled runs together without ever committing them (see
+// spillRun).
+func newSpillMergeRound(rt *dbutils.Runtime, rel handle.Relation, txn txnif.AsyncTxn, did, tid uint64, runs []*spillRun) *mergeRound {
+	round := &mergeRound{
+		rt:     rt,
+		rel:    rel,
+		txn:    txn,
+		did:    did,
+		tid:    tid,
+		blkCnt: make([]int, len(runs)),
+	}
+	for i, run := range runs {
+		round.blkCnt[i] = round.totalBlkCnt
+		round.totalBlkCnt += run.blockCnt()
+		round.sources = append(round.sources, run)
+	}
+	return round
 }
 
 // impl DisposableVecPool
-func (task *mergeObjectsTask) GetVector(typ *types.Type) (*vector.Vector, func()) {
-	v := task.rt.VectorPool.Transient.GetVector(typ)
+func (round *mergeRound) GetVector(typ *types.Type) (*vector.Vector, func()) {
+	v := round.rt.VectorPool.Transient.GetVector(typ)
 	return v.GetDownstreamVector(), v.Close
 }
 
-func (task *mergeObjectsTask) GetMPool() *mpool.MPool {
-	return task.rt.VectorPool.Transient.MPool()
+func (round *mergeRound) GetMPool() *mpool.MPool {
+	return round.rt.VectorPool.Transient.MPool()
 }
 
-func (task *mergeObjectsTask) PrepareData() ([]*batch.Batch, []*nulls.Nulls, func(), error) {
-	var err error
-	views := make([]*containers.BlockView, task.totalMergedBlkCnt)
-	releaseF := func() {
-		for _, view := range views {
-			if view != nil {
-				view.Close()
-			}
-		}
-	}
-	defer func() {
-		if err != nil {
-			releaseF()
-		}
-	}()
-	schema := task.rel.Schema().(*catalog.Schema)
-	idxs := make([]int, 0, len(schema.ColDefs)-1)
-	attrs := make([]string, 0, len(schema.ColDefs)-1)
+// columnProjection returns the non-phys-addr column indexes and names that
+// PrepareData and NextBatch both read, in schema order.
+func (round *mergeRound) columnProjection() (idxs []int, attrs []string) {
+	schema := round.rel.Schema().(*catalog.Schema)
+	idxs = make([]int, 0, len(schema.ColDefs)-1)
+	attrs = make([]string, 0, len(schema.ColDefs)-1)
 	for _, def := range schema.ColDefs {
 		if def.IsPhyAddr() {
 			continue
@@ -130,56 +171,114 @@ func (task *mergeObjectsTask) PrepareData() ([]*batch.Batch, []*nulls.Nulls, fun
 		idxs = append(idxs, def.Idx)
 		attrs = append(attrs, def.Name)
 	}
-	for i, obj := range task.mergedObjsHandle {
+	return
+}
 
-		maxBlockOffset := task.totalMergedBlkCnt
-		if i != len(task.mergedObjs)-1 {
-			maxBlockOffset = task.mergedBlkCnt[i+1]
+// objAndBlkOffsetForBlock maps a global block index (as used by blkCnt) to
+// the owning object's index and its block offset within that object.
+func (round *mergeRound) objAndBlkOffsetForBlock(blockIdx int) (objIdx, blkOffset int) {
+	for i := len(round.blkCnt) - 1; i >= 0; i-- {
+		if blockIdx >= round.blkCnt[i] {
+			return i, blockIdx - round.blkCnt[i]
 		}
-		minBlockOffset := task.mergedBlkCnt[i]
+	}
+	panic(fmt.Sprintf("block index %d out of range", blockIdx))
+}
 
-		for j := 0; j < maxBlockOffset-minBlockOffset; j++ {
-			if views[minBlockOffset+j], err = obj.GetColumnDataByIds(context.Background(), uint16(j), idxs, common.MergeAllocator); err != nil {
-				return nil, nil, nil, err
-			}
+// NextBatch streams this round's input one block at a time, in the same
+// per-object, sort-key order PrepareData reads in. Callers must invoke the
+// returned release func once they are done with the batch. A nil batch with
+// a nil error signals the iterator is exhausted.
+//
+// Today PrepareData is NextBatch's only caller, and it drains NextBatch to
+// completion before mergesort.DoMergeAndWrite ever sees a byte: that's what
+// bounds a round's resident footprint to one group's worth of blocks (see
+// runBoundedRounds), not a block at a time. Genuinely bounding the task's
+// single largest DoMergeAndWrite call -- the final one, over however many
+// spill runs bounding produced -- would mean mergesort.DoMergeAndWrite
+// pulling via NextBatch directly instead of through PrepareData's slice;
+// that's a change to the mergesort package, out of scope here.
+func (round *mergeRound) NextBatch(ctx context.Context) (*batch.Batch, *nulls.Nulls, func(), error) {
+	if round.nextBlockCursor >= round.totalBlkCnt {
+		return nil, nil, nil, nil
+	}
+	objIdx, blkOffset := round.objAndBlkOffsetForBlock(round.nextBlockCursor)
+	idxs, attrs := round.columnProjection()
+
+	view, err := round.sources[objIdx].GetColumnDataByIds(ctx, uint16(blkOffset), idxs, common.MergeAllocator)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	bat := batch.New(true, attrs)
+	if len(attrs) != len(view.Columns) {
+		view.Close()
+		return nil, nil, nil, fmt.Errorf("mismatch %v, %v, %v", attrs, len(attrs), len(view.Columns))
+	}
+	for i, col := range view.Columns {
+		bat.Vecs[i] = col.GetData().GetDownstreamVector()
+	}
+	bat.SetRowCount(view.Columns[0].Length())
+
+	round.nextBlockCursor++
+	return bat, view.DeleteMask, view.Close, nil
+}
+
+// PrepareData materializes every block of the round in memory, via
+// successive NextBatch calls, before mergesort.DoMergeAndWrite runs.
+//
+// mergeObjectsTask.runBoundedRounds keeps each of its own intermediate,
+// per-group rounds within task.rt.MergePolicy.MemBudget by construction, but
+// it hands its own output -- one round over every spilled run combined --
+// back to the caller for one more, unbounded PrepareData call (see Execute):
+// that round's totalBlkCnt is not meaningfully smaller than the task's
+// original input, since merging doesn't shrink block count. So PrepareData
+// itself enforces no budget; only runBoundedRounds' own intermediate calls
+// are actually bounded.
+func (round *mergeRound) PrepareData() ([]*batch.Batch, []*nulls.Nulls, func(), error) {
+	batches := make([]*batch.Batch, 0, round.totalBlkCnt)
+	dels := make([]*nulls.Nulls, 0, round.totalBlkCnt)
+	releases := make([]func(), 0, round.totalBlkCnt)
+	releaseF := func() {
+		for _, release := range releases {
+			release()
 		}
 	}
 
-	batches := make([]*batch.Batch, 0, task.totalMergedBlkCnt)
-	dels := make([]*nulls.Nulls, 0, task.totalMergedBlkCnt)
-	for _, view := range views {
-		batch := batch.New(true, attrs)
-		if len(attrs) != len(view.Columns) {
-			panic(fmt.Sprintf("mismatch %v, %v, %v", attrs, len(attrs), len(view.Columns)))
+	for {
+		bat, del, release, err := round.NextBatch(context.Background())
+		if err != nil {
+			releaseF()
+			return nil, nil, nil, err
 		}
-		for i, col := range view.Columns {
-			batch.Vecs[i] = col.GetData().GetDownstreamVector()
+		if bat == nil {
+			break
 		}
-		batch.SetRowCount(view.Columns[0].Length())
-		batches = append(batches, batch)
-		dels = append(dels, view.DeleteMask)
+		batches = append(batches, bat)
+		dels = append(dels, del)
+		releases = append(releases, release)
 	}
 
 	return batches, dels, releaseF, nil
 }
 
-func (task *mergeObjectsTask) PrepareCommitEntry() *mergesort.MergeCommitEntry {
-	schema := task.rel.Schema().(*catalog.Schema)
+func (round *mergeRound) PrepareCommitEntry() *mergesort.MergeCommitEntry {
+	schema := round.rel.Schema().(*catalog.Schema)
 	commitEntry := &mergesort.MergeCommitEntry{}
-	commitEntry.DbID = task.did
-	commitEntry.TableID = task.tid
+	commitEntry.DbID = round.did
+	commitEntry.TableID = round.tid
 	commitEntry.Tablename = schema.Name
-	commitEntry.StartTs = task.txn.GetStartTS()
-	for _, o := range task.mergedObjs {
+	commitEntry.StartTs = round.txn.GetStartTS()
+	for _, o := range round.objs {
 		commitEntry.MergedObjs = append(commitEntry.MergedObjs, o.GetObjectStats())
 	}
-	task.commitEntry = commitEntry
+	round.commitEntry = commitEntry
 	// leave mapping to ReadMergeAndWrite
 	return commitEntry
 }
 
-func (task *mergeObjectsTask) PrepareNewWriterFunc() func() *blockio.BlockWriter {
-	schema := task.rel.Schema().(*catalog.Schema)
+func (round *mergeRound) PrepareNewWriterFunc() func() *blockio.BlockWriter {
+	schema := round.rel.Schema().(*catalog.Schema)
 	seqnums := make([]uint16, 0, len(schema.ColDefs)-1)
 	for _, def := range schema.ColDefs {
 		if def.IsPhyAddr() {
@@ -196,7 +295,234 @@ func (task *mergeObjectsTask) PrepareNewWriterFunc() func() *blockio.BlockWriter
 	} else if schema.HasSortKey() {
 		sortkeyPos = schema.GetSingleSortKeyIdx()
 	}
-	return mergesort.GetMustNewWriter(task.rt.Fs.Service, schema.Version, seqnums, sortkeyPos, sortkeyIsPK)
+	return mergesort.GetMustNewWriter(round.rt.Fs.Service, schema.Version, seqnums, sortkeyPos, sortkeyIsPK)
+}
+
+// varlenaRowGuess is the bytes assumed for a varlena column's (varchar,
+// text, blob, ...) out-of-line payload when estimating resident size, since
+// the real length isn't known before a block is actually read.
+const varlenaRowGuess = 64
+
+// rowWidth estimates the in-memory bytes one row of the round's schema
+// occupies by summing each column's real encoded width, rather than a flat
+// per-column guess: a schema with a handful of bigint columns and one with
+// dozens of wide varlena columns have very different resident footprints
+// per block, and useBoundedRounds/boundedRoundBlkCnt need that distinction
+// to avoid either bounding too eagerly or blowing the memory budget.
+func (round *mergeRound) rowWidth() int64 {
+	schema := round.rel.Schema().(*catalog.Schema)
+	var width int64
+	for _, def := range schema.ColDefs {
+		if def.IsPhyAddr() {
+			continue
+		}
+		if def.Type.IsVarlen() {
+			width += varlenaRowGuess
+		} else {
+			width += int64(def.Type.Size())
+		}
+	}
+	return width
+}
+
+// resident estimates the bytes a fully materialized round would hold, used
+// both to size bounded rounds and to report MergePeakBytes.
+func (round *mergeRound) resident() int64 {
+	schema := round.rel.Schema().(*catalog.Schema)
+	return round.rowWidth() * int64(schema.BlockMaxRows) * int64(round.totalBlkCnt)
+}
+
+type mergeObjectsTask struct {
+	*tasks.BaseTask
+	*mergeRound
+	createdBObjs []*catalog.ObjectEntry
+}
+
+func NewMergeObjectsTask(
+	ctx *tasks.Context, txn txnif.AsyncTxn,
+	mergedObjs []*catalog.ObjectEntry,
+	rt *dbutils.Runtime,
+) (task *mergeObjectsTask, err error) {
+	if len(mergedObjs) == 0 {
+		panic("empty mergedObjs")
+	}
+	did := mergedObjs[0].GetTable().GetDB().ID
+	database, err := txn.GetDatabaseByID(did)
+	if err != nil {
+		return nil, err
+	}
+	tid := mergedObjs[0].GetTable().ID
+	rel, err := database.GetRelationByID(tid)
+	if err != nil {
+		return nil, err
+	}
+	round, err := newMergeRound(rt, rel, txn, did, tid, mergedObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	task = &mergeObjectsTask{
+		mergeRound:   round,
+		createdBObjs: make([]*catalog.ObjectEntry, 0),
+	}
+	task.BaseTask = tasks.NewBaseTask(task, tasks.DataCompactionTask, ctx)
+	return
+}
+
+// useBoundedRounds reports whether the merge should be split into several
+// budget-sized rounds (see runBoundedRounds) instead of running as a single
+// mergesort.DoMergeAndWrite pass over every merged object. Bounding is opt-in:
+// task.rt.MergePolicy.MemBudget's zero value leaves every merge on the
+// previous, unbounded single-pass path, matching MergePolicy's doc.
+func (task *mergeObjectsTask) useBoundedRounds() bool {
+	budget := task.rt.MergePolicy.MemBudget
+	if budget <= 0 {
+		return false
+	}
+	return task.resident() > budget
+}
+
+// boundedRoundBlkCnt converts task.rt.MergePolicy.MemBudget into a block
+// count each bounded round may hold resident. Only called once useBoundedRounds
+// has already confirmed MemBudget > 0.
+func (task *mergeObjectsTask) boundedRoundBlkCnt() int {
+	budget := task.rt.MergePolicy.MemBudget
+	schema := task.rel.Schema().(*catalog.Schema)
+	perBlock := task.rowWidth() * int64(schema.BlockMaxRows)
+	if perBlock <= 0 {
+		return task.totalBlkCnt
+	}
+	if n := int(budget / perBlock); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// partitionByBlockBudget greedily bins the indexes of blkCnt into groups
+// whose total block count stays at or under budget, preserving input order.
+// An index whose own block count already exceeds budget gets an (oversized)
+// group of its own, since individual objects/runs aren't split here.
+func partitionByBlockBudget(blkCnt []int, budget int) [][]int {
+	var groups [][]int
+	var cur []int
+	curBlkCnt := 0
+	for i, cnt := range blkCnt {
+		if len(cur) > 0 && curBlkCnt+cnt > budget {
+			groups = append(groups, cur)
+			cur = nil
+			curBlkCnt = 0
+		}
+		cur = append(cur, i)
+		curBlkCnt += cnt
+	}
+	if len(cur) > 0 {
+		groups = append(groups, cur)
+	}
+	return groups
+}
+
+// spillRound runs mergesort.DoMergeAndWrite's already-written output through
+// perfcounter and wraps it as spillRuns instead of committing it, so the
+// caller can feed it into a later round without ever registering it with
+// the catalog or notifying MergeEventSink for it.
+func (task *mergeObjectsTask) spillRound(ctx context.Context, round *mergeRound) []*spillRun {
+	perfcounter.Update(ctx, func(counter *perfcounter.CounterSet) {
+		counter.TAE.Object.MergeSpillRounds.Add(1)
+		counter.TAE.Object.MergeSpillBytes.Add(round.resident())
+	})
+	runs := make([]*spillRun, 0, len(round.commitEntry.CreatedObjectStats))
+	for _, stats := range round.commitEntry.CreatedObjectStats {
+		runs = append(runs, &spillRun{fs: task.rt.Fs, stats: stats})
+	}
+	return runs
+}
+
+// removeSpillRuns deletes every run's backing fs object, logging (without
+// failing the task) on each individual removal error. None of runs was ever
+// registered with the catalog, so nothing else can be referencing them by
+// the time a caller decides they're done: either runBoundedRounds is
+// abandoning an already-spilled group because a later one failed, or
+// Execute's final DoMergeAndWrite has read them and the task is finishing,
+// successfully or not.
+func (task *mergeObjectsTask) removeSpillRuns(ctx context.Context, runs []*spillRun) {
+	for _, run := range runs {
+		if rmErr := task.rt.Fs.Service.Delete(ctx, run.path()); rmErr != nil {
+			logutil.Warn("[Mergeblocks] failed to remove spilled merge run",
+				common.OperationField(task.Name()),
+				common.AnyField("path", run.path()),
+				common.AnyField("error", rmErr),
+			)
+		}
+	}
+}
+
+// runBoundedRounds reduces the task's input in budget-sized passes so no
+// single mergesort.DoMergeAndWrite call in this function holds more than
+// boundedRoundBlkCnt blocks resident, except a group made of one object that
+// alone exceeds it (see partitionByBlockBudget). Each pass's output is spilled to rt.Fs
+// as a spillRun (read back via NextBatch, not a catalog handle) rather than
+// committed: unlike committing every intermediate round to the catalog,
+// this never exposes a transient, not-really-there object to a reader,
+// never reads a same-txn uncommitted catalog object back, and never fires
+// MergeEventSink until the caller commits the true, final result.
+//
+// It does only this one pass of bounding, then hands every resulting
+// spillRun straight to the final round: re-merging the (already sorted,
+// delete-free) runs again to shrink their count doesn't reduce total block
+// count, so for any input well over budget it never converges, looping
+// forever while rewriting and deleting fs objects to no effect.
+//
+// That final round is returned, not run, so the caller still owns the last
+// mergesort.DoMergeAndWrite call (see Execute) — and that call's own
+// PrepareData still materializes every one of the final round's blocks at
+// once, since mergesort.DoMergeAndWrite pulls via PrepareData rather than
+// NextBatch. So MemBudget only bounds the per-group passes below; it does
+// not bound the task's overall peak residency, which this function reports
+// back via peakBytes so Execute's MergePeakBytes reflects it honestly.
+func (task *mergeObjectsTask) runBoundedRounds(ctx context.Context, sortkeyPos, blockMaxRows int) (final *mergeRound, peakBytes int64, err error) {
+	groupBlkCnt := task.boundedRoundBlkCnt()
+
+	objBlkCnt := make([]int, len(task.objs))
+	for i, obj := range task.objs {
+		objBlkCnt[i] = obj.BlockCnt()
+	}
+	groups := partitionByBlockBudget(objBlkCnt, groupBlkCnt)
+	if len(groups) <= 1 {
+		// The whole input already fits one round; nothing to bound.
+		return task.mergeRound, task.mergeRound.resident(), nil
+	}
+
+	runs := make([]*spillRun, 0, len(groups))
+	for _, idxs := range groups {
+		group := make([]*catalog.ObjectEntry, len(idxs))
+		for i, idx := range idxs {
+			group[i] = task.objs[idx]
+		}
+		round, err := newMergeRound(task.rt, task.rel, task.txn, task.did, task.tid, group)
+		if err != nil {
+			task.removeSpillRuns(ctx, runs)
+			return nil, 0, err
+		}
+		if r := round.resident(); r > peakBytes {
+			peakBytes = r
+		}
+		if err = mergesort.DoMergeAndWrite(ctx, sortkeyPos, blockMaxRows, round); err != nil {
+			// Every earlier group in this loop already spilled its output to
+			// rt.Fs; none of it was ever registered with the catalog, so
+			// aborting here without removing it would orphan those objects.
+			task.removeSpillRuns(ctx, runs)
+			return nil, 0, err
+		}
+		runs = append(runs, task.spillRound(ctx, round)...)
+	}
+
+	final = newSpillMergeRound(task.rt, task.rel, task.txn, task.did, task.tid, runs)
+	// The final round's commit entry must still describe the task's real,
+	// original objects as MergedObjs (not the spill runs it actually reads
+	// here), so the caller's HandleMergeEntryInTxn soft-deletes the right
+	// things and MergeEventSink's entry reports the true merge.
+	final.objs = task.objs
+	return final, peakBytes, nil
 }
 
 func (task *mergeObjectsTask) Execute(ctx context.Context) (err error) {
@@ -215,13 +541,52 @@ func (task *mergeObjectsTask) Execute(ctx context.Context) (err error) {
 	if schema.HasSortKey() {
 		sortkeyPos = schema.GetSingleSortKeyIdx()
 	}
+	blockMaxRows := int(schema.BlockMaxRows)
+
+	round := task.mergeRound
+	var peak int64
+	if task.useBoundedRounds() {
+		phaseDesc = "0-BoundedRounds"
+		if round, peak, err = task.runBoundedRounds(ctx, sortkeyPos, blockMaxRows); err != nil {
+			return err
+		}
+	}
+
+	// Any source this round reads is a spillRun (runBoundedRounds'
+	// intermediate output) rather than a real catalog object; clean those up
+	// once this call below is done with them, whether it (or anything after
+	// it) succeeds or fails -- a partial failure here must not leave them
+	// orphaned on rt.Fs, since it was never registered with the catalog and
+	// nothing else can be referencing it.
+	defer func() {
+		var spilled []*spillRun
+		for _, src := range round.sources {
+			if run, ok := src.(*spillRun); ok {
+				spilled = append(spilled, run)
+			}
+		}
+		task.removeSpillRuns(ctx, spilled)
+	}()
+
 	phaseDesc = "1-DoMergeAndWrite"
-	if err = mergesort.DoMergeAndWrite(ctx, sortkeyPos, int(schema.BlockMaxRows), task); err != nil {
+	// The final DoMergeAndWrite call below materializes round's blocks via
+	// PrepareData regardless of whether bounding ran (see runBoundedRounds),
+	// so it's always part of the task's true peak residency, not just the
+	// bounded-rounds case.
+	if r := round.resident(); r > peak {
+		peak = r
+	}
+	if peak > 0 {
+		perfcounter.Update(ctx, func(counter *perfcounter.CounterSet) {
+			counter.TAE.Object.MergePeakBytes.Add(peak)
+		})
+	}
+	if err = mergesort.DoMergeAndWrite(ctx, sortkeyPos, blockMaxRows, round); err != nil {
 		return err
 	}
 
 	phaseDesc = "2-HandleMergeEntryInTxn"
-	if task.createdBObjs, err = HandleMergeEntryInTxn(task.txn, task.commitEntry, task.rt); err != nil {
+	if task.createdBObjs, err = HandleMergeEntryInTxn(task.txn, round.commitEntry, task.rt); err != nil {
 		return err
 	}
 
@@ -290,6 +655,13 @@ func HandleMergeEntryInTxn(txn txnif.AsyncTxn, entry *mergesort.MergeCommitEntry
 		return nil, err
 	}
 
+	// Only notify CDC-style subscribers once LogTxnEntry has accepted the
+	// merge entry, not before, so a merge that fails validation earlier in
+	// this function never reaches a sink.
+	if sink := rt.MergeEventSink; sink != nil {
+		sink.OnMerge(entry, createdObjs)
+	}
+
 	return createdObjs, nil
 }
 
@@ -0,0 +1,79 @@
+// Copyright 2021 Matrix Origin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// boundedRoundBlkCnt/useBoundedRounds, rowWidth/resident, and
+// runBoundedRounds' own grouping/spill/cleanup behavior all need a
+// catalog.Schema and handle.Relation to exercise, neither of which has a
+// usable test fixture in this package today; partitionByBlockBudget is the
+// one piece of that machinery with no such dependency, so it's covered here
+// on its own.
+func TestPartitionByBlockBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		blkCnt []int
+		budget int
+		want   [][]int
+	}{
+		{
+			name:   "empty input",
+			blkCnt: nil,
+			budget: 10,
+			want:   nil,
+		},
+		{
+			name:   "everything fits one group",
+			blkCnt: []int{1, 2, 3},
+			budget: 10,
+			want:   [][]int{{0, 1, 2}},
+		},
+		{
+			name:   "exact budget boundary splits after the filling element",
+			blkCnt: []int{5, 5, 5},
+			budget: 10,
+			want:   [][]int{{0, 1}, {2}},
+		},
+		{
+			name:   "single oversized element gets its own group",
+			blkCnt: []int{20},
+			budget: 10,
+			want:   [][]int{{0}},
+		},
+		{
+			name:   "oversized element doesn't merge with neighbors on either side",
+			blkCnt: []int{3, 20, 3},
+			budget: 10,
+			want:   [][]int{{0}, {1}, {2}},
+		},
+		{
+			name:   "every element exactly at budget gets its own group",
+			blkCnt: []int{10, 10, 10},
+			budget: 10,
+			want:   [][]int{{0}, {1}, {2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, partitionByBlockBudget(tt.blkCnt, tt.budget))
+		})
+	}
+}